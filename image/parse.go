@@ -2,14 +2,28 @@ package image
 
 import (
 	"strings"
+
+	uimage "github.com/urkonn/keel/util/image"
 )
 
 // Reference is an opaque object that include identifier such as a name, tag, repository, registry, etc...
 type Reference struct {
-	named Named
+	named uimage.Named
 	tag   string
 }
 
+// Repository is an object created from Named interface
+type Repository struct {
+	Name       string // Name returns the image's name. (ie: debian[:8.2])
+	Repository string // Repository returns the image's repository. (ie: registry/name)
+	Registry   string // Registry returns the image's registry. (ie: host[:port])
+	Scheme     string // Registry scheme. (ie: http)
+	ShortName  string // ShortName returns the image's name (ie: debian)
+	Remote     string // Remote returns the image's remote identifier. (ie: registry/name[:tag])
+	Tag        string // Tag returns the image's tag (or digest).
+	Familiar   string // Familiar returns the image's familiar form (ie: debian[:8.2], without the default registry or "library/" prefix).
+}
+
 // Name returns the image's name. (ie: debian[:8.2])
 func (r Reference) Name() string {
 	return r.named.RemoteName() + r.tag
@@ -59,42 +73,66 @@ func clean(url string) string {
 // Parse returns a Reference from analyzing the given remote identifier.
 func Parse(remote string) (*Reference, error) {
 
-	n, err := ParseNamed(clean(remote))
+	n, err := uimage.ParseNamed(clean(remote))
 
 	if err != nil {
 		return nil, err
 	}
 
-	n = WithDefaultTag(n)
+	n = uimage.WithDefaultTag(n)
 
 	var t string
 	switch x := n.(type) {
-	case Canonical:
+	case uimage.Canonical:
 		t = "@" + x.Digest().String()
-	case NamedTagged:
+	case uimage.NamedTagged:
 		t = ":" + x.Tag()
 	}
 
 	return &Reference{named: n, tag: t}, nil
 }
 
+// ParseWith returns a Reference from analyzing the given remote identifier,
+// using n's registry settings (default registry, mirrors, repo prefix)
+// instead of the package defaults.
+func ParseWith(n *uimage.Normalizer, remote string) (*Reference, error) {
+
+	named, err := n.ParseNamed(clean(remote))
+
+	if err != nil {
+		return nil, err
+	}
+
+	named = uimage.WithDefaultTag(named)
+
+	var t string
+	switch x := named.(type) {
+	case uimage.Canonical:
+		t = "@" + x.Digest().String()
+	case uimage.NamedTagged:
+		t = ":" + x.Tag()
+	}
+
+	return &Reference{named: named, tag: t}, nil
+}
+
 // ParseRepo - parses remote
 // pretty much the same as Parse but better for testing
 func ParseRepo(remote string) (*Repository, error) {
 
-	n, err := ParseNamed(clean(remote))
+	n, err := uimage.ParseNamed(clean(remote))
 
 	if err != nil {
 		return nil, err
 	}
 
-	n = WithDefaultTag(n)
+	n = uimage.WithDefaultTag(n)
 
 	var t string
 	switch x := n.(type) {
-	case Canonical:
+	case uimage.Canonical:
 		t = "@" + x.Digest().String()
-	case NamedTagged:
+	case uimage.NamedTagged:
 		t = ":" + x.Tag()
 	}
 
@@ -107,5 +145,6 @@ func ParseRepo(remote string) (*Repository, error) {
 		Remote:     ref.Remote(),
 		ShortName:  ref.ShortName(),
 		Tag:        ref.Tag(),
+		Familiar:   uimage.FamiliarString(n),
 	}, nil
 }
@@ -0,0 +1,14 @@
+package image
+
+import "testing"
+
+func TestReferenceRegistryPreservesHostnameCasing(t *testing.T) {
+	ref, err := Parse("Registry.Example.com/foo/bar")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if got, want := ref.Registry(), "Registry.Example.com"; got != want {
+		t.Errorf("Registry() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,146 @@
+package image
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	digest "github.com/opencontainers/go-digest"
+
+	uimage "github.com/urkonn/keel/util/image"
+)
+
+var (
+	// ErrNoDomain is returned by ParseStrict when s has no explicit
+	// registry domain, eg "ubuntu" or "myorg/app".
+	ErrNoDomain = errors.New("reference is missing an explicit registry domain")
+	// ErrInvalidTag is returned when the tag portion of a reference does
+	// not match the OCI tag grammar.
+	ErrInvalidTag = errors.New("invalid reference format: invalid tag")
+	// ErrInvalidDigest is returned when the digest portion of a reference
+	// does not match the OCI digest grammar.
+	ErrInvalidDigest = errors.New("invalid reference format: invalid digest")
+	// ErrInvalidPathComponent is returned when a repository path component
+	// does not match the OCI path-component grammar.
+	ErrInvalidPathComponent = errors.New("invalid reference format: invalid path component")
+)
+
+// These mirror the containerd/OCI distribution-spec grammar:
+//
+//	reference       := name [ ":" tag ] [ "@" digest ]
+//	name            := domain "/" path-component *( "/" path-component )
+//	domain-component := [a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?
+//	path-component  := [a-z0-9]+(([._]|__|[-]+)[a-z0-9]+)*
+//	tag             := [\w][\w.-]{0,127}
+//	digest          := algo ":" [0-9a-fA-F]{32,}
+const (
+	domainComponentGrammar = `[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?`
+	pathComponentGrammar   = `[a-z0-9]+(?:(?:[._]|__|[-]+)[a-z0-9]+)*`
+)
+
+var (
+	domainRegexp = regexp.MustCompile(`^` + domainComponentGrammar + `(?:\.` + domainComponentGrammar + `)*(?::[0-9]+)?$`)
+	pathRegexp   = regexp.MustCompile(`^` + pathComponentGrammar + `(?:/` + pathComponentGrammar + `)*$`)
+	tagRegexp    = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	digestRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9]*(?:[-_+.][a-zA-Z][a-zA-Z0-9]*)*:[0-9a-fA-F]{32,}$`)
+)
+
+// ParseStrict parses s against the OCI distribution-spec grammar, rejecting
+// everything the permissive ParseNamed/Parse accept for convenience: a
+// registry domain must be given explicitly (no default hostname injection,
+// no "library/" shortcut), and tags/digests must match the grammar exactly.
+// It's meant for validating admission policies written against
+// OCI-conformant registries, where silent rewriting would be surprising.
+//
+// Unlike Parse, a grammar violation is always reported as one of
+// ErrNoDomain, ErrInvalidTag, ErrInvalidDigest or ErrInvalidPathComponent,
+// so callers can distinguish the cause. A name that matches the grammar but
+// is longer than the reference package's 255-character limit is rejected
+// with that package's own error instead.
+func ParseStrict(s string) (*Reference, error) {
+	name := s
+
+	var dgst string
+	if i := strings.IndexRune(name, '@'); i != -1 {
+		dgst = name[i+1:]
+		name = name[:i]
+	}
+
+	name, tag := splitStrictTag(name)
+
+	i := strings.IndexRune(name, '/')
+	if i == -1 || !(strings.ContainsAny(name[:i], ".:") || name[:i] == "localhost") {
+		// Unlike the permissive splitHostname, a label with no dot, port
+		// or "localhost" is never treated as an implicit domain here - it
+		// must be rejected, not defaulted to the configured registry.
+		return nil, ErrNoDomain
+	}
+	domain, remainder := name[:i], name[i+1:]
+	if !domainRegexp.MatchString(domain) {
+		return nil, ErrNoDomain
+	}
+	if !pathRegexp.MatchString(remainder) {
+		return nil, ErrInvalidPathComponent
+	}
+	if tag != "" && !tagRegexp.MatchString(tag) {
+		return nil, ErrInvalidTag
+	}
+	if dgst != "" && !digestRegexp.MatchString(dgst) {
+		return nil, ErrInvalidDigest
+	}
+
+	// Build the Named value through a no-op Normalizer (empty
+	// DefaultRegistry/DefaultRepoPrefix/LegacyRegistries) rather than
+	// uimage.WithName/uimage.DefaultNormalizer, so the explicit domain we
+	// just validated is never rewritten to the configured default registry.
+	named, err := (&uimage.Normalizer{}).WithName(domain + "/" + remainder)
+	if err != nil {
+		return nil, err
+	}
+
+	var t string
+	switch {
+	case dgst != "":
+		canonical, err := uimage.WithDigest(named, digest.Digest(dgst))
+		if err != nil {
+			return nil, err
+		}
+		named, t = canonical, "@"+dgst
+	case tag != "":
+		tagged, err := uimage.WithTag(named, tag)
+		if err != nil {
+			return nil, err
+		}
+		named, t = tagged, ":"+tag
+	}
+
+	return &Reference{named: named, tag: t}, nil
+}
+
+// splitStrictTag splits the trailing ":tag" off name, if any. It only
+// treats the last colon as a tag separator when nothing after it belongs
+// to a later path component, so a registry port (eg "host:5000/repo") is
+// never mistaken for a tag.
+func splitStrictTag(name string) (string, string) {
+	i := strings.LastIndex(name, ":")
+	if i < 0 || strings.ContainsRune(name[i:], '/') {
+		return name, ""
+	}
+	return name[:i], name[i+1:]
+}
+
+// Validate re-runs ParseStrict's checks against an already-constructed
+// Reference (its Remote() form), eg one produced by the permissive Parse,
+// and reports the first OCI-grammar violation found.
+func (r *Reference) Validate() error {
+	_, err := ParseStrict(r.Remote())
+	return err
+}
+
+// Validate re-runs ParseStrict's checks against an already-constructed
+// Repository, eg one deserialized from YAML/JSON without going through
+// ParseRepo, and reports the first OCI-grammar violation found.
+func (rep *Repository) Validate() error {
+	_, err := ParseStrict(rep.Remote)
+	return err
+}
@@ -0,0 +1,94 @@
+package image
+
+import (
+	"testing"
+
+	uimage "github.com/urkonn/keel/util/image"
+)
+
+func TestParseStrict(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{name: "no domain", input: "ubuntu", wantErr: ErrNoDomain},
+		{name: "no domain, multiple path components", input: "myorg/app", wantErr: ErrNoDomain},
+		{name: "invalid tag", input: "registry.example.com/foo:!bad", wantErr: ErrInvalidTag},
+		{name: "invalid digest", input: "registry.example.com/foo@sha256:tooshort", wantErr: ErrInvalidDigest},
+		{name: "invalid path component", input: "registry.example.com/Foo", wantErr: ErrInvalidPathComponent},
+		{name: "valid, domain and tag", input: "registry.example.com/foo/bar:v1", wantErr: nil},
+		{name: "valid, domain and digest", input: "registry.example.com/foo@sha256:" + hex64, wantErr: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseStrict(tt.input)
+			if err != tt.wantErr {
+				t.Fatalf("ParseStrict(%q) = %v, want %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// hex64 is a syntactically valid sha256 hex digest body, used to build
+// "algo:hex" digests in tests.
+const hex64 = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+// TestParseStrictTagAndDigestTogether pins ParseStrict's current behavior
+// when a reference carries both a tag and a digest: the tag is validated
+// against tagRegexp but then silently dropped in favor of the digest, since
+// the dgst != "" branch is checked first.
+func TestParseStrictTagAndDigestTogether(t *testing.T) {
+	ref, err := ParseStrict("registry.example.com/foo:v1@sha256:" + hex64)
+	if err != nil {
+		t.Fatalf("ParseStrict: %v", err)
+	}
+
+	if got, want := ref.Tag(), "sha256:"+hex64; got != want {
+		t.Errorf("Tag() = %q, want %q (tag %q should have been dropped)", got, want, "v1")
+	}
+	if got := ref.Remote(); got != "registry.example.com/foo@sha256:"+hex64 {
+		t.Errorf("Remote() = %q, should not contain the dropped tag", got)
+	}
+}
+
+func TestReferenceValidate(t *testing.T) {
+	ref, err := ParseStrict("registry.example.com/foo/bar:v1")
+	if err != nil {
+		t.Fatalf("ParseStrict: %v", err)
+	}
+	if err := ref.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	named, err := uimage.ParseNamed("ubuntu")
+	if err != nil {
+		t.Fatalf("ParseNamed: %v", err)
+	}
+	bad := &Reference{named: named, tag: ":!bad"}
+	if err := bad.Validate(); err != ErrInvalidTag {
+		t.Errorf("Validate() = %v, want %v", err, ErrInvalidTag)
+	}
+}
+
+func TestRepositoryValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		remote  string
+		wantErr error
+	}{
+		{name: "no domain", remote: "myorg/app", wantErr: ErrNoDomain},
+		{name: "invalid path component", remote: "registry.example.com/Foo", wantErr: ErrInvalidPathComponent},
+		{name: "valid", remote: "registry.example.com/foo/bar:v1", wantErr: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rep := &Repository{Remote: tt.remote}
+			if err := rep.Validate(); err != tt.wantErr {
+				t.Fatalf("Validate() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package image
+
+import (
+	"fmt"
+
+	digest "github.com/opencontainers/go-digest"
+)
+
+// digestReference is a Reference that carries nothing but a digest, so that
+// callers which only have an image ID (no repository name) can still be
+// handed a uniform Canonical value.
+type digestReference struct {
+	digest digest.Digest
+}
+
+func (d digestReference) Name() string       { return "" }
+func (d digestReference) String() string     { return d.digest.String() }
+func (d digestReference) FullName() string   { return "" }
+func (d digestReference) Hostname() string   { return "" }
+func (d digestReference) RemoteName() string { return "" }
+func (d digestReference) Digest() digest.Digest {
+	return d.digest
+}
+
+// isHexIdentifier reports whether s looks like a bare image ID: a hex
+// string of at least 7 characters, with no algorithm prefix.
+func isHexIdentifier(s string) bool {
+	if len(s) < 7 {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+		case r >= 'a' && r <= 'f':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ParseAnyReference parses s, which may be:
+//   - a full named reference, as accepted by ParseNamed ("ubuntu:latest"),
+//   - a full "algo:hex" digest ("sha256:abcdef..."), or
+//   - a short hex identifier of 7 or more characters ("abcdef0"), which is
+//     resolved against ds.
+//
+// ds may be nil, in which case short identifiers are rejected rather than
+// resolved. This is meant for callers that only have an image ID on hand,
+// eg from "docker inspect", an admission webhook payload, or an OCI index.
+func ParseAnyReference(s string, ds *DigestSet) (Reference, error) {
+	if dgst, err := digest.Parse(s); err == nil {
+		return digestReference{digest: dgst}, nil
+	}
+
+	if isHexIdentifier(s) {
+		if ds == nil {
+			return nil, fmt.Errorf("%q looks like a short image ID, but no DigestSet was given to resolve it", s)
+		}
+		dgst, err := ds.Lookup(s)
+		if err != nil {
+			return nil, err
+		}
+		return digestReference{digest: dgst}, nil
+	}
+
+	return ParseNamed(s)
+}
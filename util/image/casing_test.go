@@ -0,0 +1,27 @@
+package image
+
+import "testing"
+
+func TestNormalizerHostnameCasing(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "uppercase domain", input: "Registry.Example.com/foo/bar"},
+		{name: "uppercase localhost with port", input: "LOCALHOST:5000/x"},
+		{name: "uppercase path component", input: "HOST/UPPER/path", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DefaultNormalizer.ParseNamed(tt.input)
+			if tt.wantErr && err == nil {
+				t.Fatalf("ParseNamed(%q) succeeded, want error", tt.input)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("ParseNamed(%q) = %v, want success", tt.input, err)
+			}
+		})
+	}
+}
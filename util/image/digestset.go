@@ -0,0 +1,111 @@
+package image
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	digest "github.com/opencontainers/go-digest"
+)
+
+var (
+	// ErrDigestNotFound is returned by DigestSet.Lookup when no registered
+	// digest matches the given prefix.
+	ErrDigestNotFound = errors.New("digest not found")
+	// ErrDigestAmbiguous is returned by DigestSet.Lookup when more than one
+	// registered digest matches the given prefix.
+	ErrDigestAmbiguous = errors.New("ambiguous digest, use a longer prefix for disambiguation")
+)
+
+// DigestSet keeps track of digests Keel has already seen (eg: from an OCI
+// index, an admission webhook payload, or a "docker inspect" response) so
+// that later callers can resolve a short image ID back to the full digest
+// through ParseAnyReference.
+type DigestSet struct {
+	mu      sync.RWMutex
+	entries map[digest.Algorithm][]digest.Digest
+}
+
+// NewDigestSet returns an empty, ready to use DigestSet.
+func NewDigestSet() *DigestSet {
+	return &DigestSet{entries: make(map[digest.Algorithm][]digest.Digest)}
+}
+
+// Add registers d with the set. Adding the same digest twice is a no-op.
+func (ds *DigestSet) Add(d digest.Digest) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	algo := d.Algorithm()
+	entries := ds.entries[algo]
+	i := sort.Search(len(entries), func(i int) bool { return entries[i] >= d })
+	if i < len(entries) && entries[i] == d {
+		return
+	}
+	entries = append(entries, "")
+	copy(entries[i+1:], entries[i:])
+	entries[i] = d
+	ds.entries[algo] = entries
+}
+
+// Remove unregisters d from the set, if present.
+func (ds *DigestSet) Remove(d digest.Digest) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	algo := d.Algorithm()
+	entries := ds.entries[algo]
+	i := sort.Search(len(entries), func(i int) bool { return entries[i] >= d })
+	if i < len(entries) && entries[i] == d {
+		ds.entries[algo] = append(entries[:i], entries[i+1:]...)
+	}
+}
+
+// Lookup resolves s to the single registered digest it identifies. s may be
+// a full "algo:hex" digest or just a hex prefix, in which case the
+// algorithm defaults to sha256. It returns ErrDigestNotFound when no entry
+// matches and ErrDigestAmbiguous when more than one does.
+func (ds *DigestSet) Lookup(s string) (digest.Digest, error) {
+	algo := digest.SHA256
+	prefix := s
+	if i := strings.IndexRune(s, ':'); i != -1 {
+		algo = digest.Algorithm(s[:i])
+		prefix = s[i+1:]
+	}
+
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	entries := ds.entries[algo]
+	start := sort.Search(len(entries), func(i int) bool {
+		return entries[i].Encoded() >= prefix
+	})
+
+	var found digest.Digest
+	for _, d := range entries[start:] {
+		if !strings.HasPrefix(d.Encoded(), prefix) {
+			break
+		}
+		if found != "" {
+			return "", ErrDigestAmbiguous
+		}
+		found = d
+	}
+	if found == "" {
+		return "", ErrDigestNotFound
+	}
+	return found, nil
+}
+
+// All returns every digest currently registered in the set.
+func (ds *DigestSet) All() []digest.Digest {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	var all []digest.Digest
+	for _, entries := range ds.entries {
+		all = append(all, entries...)
+	}
+	return all
+}
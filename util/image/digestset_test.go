@@ -0,0 +1,111 @@
+package image
+
+import (
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+)
+
+func TestDigestSetLookup(t *testing.T) {
+	ds := NewDigestSet()
+
+	d1 := digest.Digest("sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	d2 := digest.Digest("sha256:aaaabbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+	ds.Add(d1)
+	ds.Add(d2)
+
+	got, err := ds.Lookup("aaaa")
+	if err != ErrDigestAmbiguous {
+		t.Fatalf("Lookup(%q) = (%q, %v), want ErrDigestAmbiguous", "aaaa", got, err)
+	}
+
+	got, err = ds.Lookup("aaaaa")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if got != d1 {
+		t.Errorf("Lookup(%q) = %q, want %q", "aaaaa", got, d1)
+	}
+
+	if _, err := ds.Lookup("ffffff"); err != ErrDigestNotFound {
+		t.Errorf("Lookup(%q) = %v, want ErrDigestNotFound", "ffffff", err)
+	}
+}
+
+func TestDigestSetRemove(t *testing.T) {
+	ds := NewDigestSet()
+
+	d1 := digest.Digest("sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	ds.Add(d1)
+	ds.Remove(d1)
+
+	if _, err := ds.Lookup("aaaaaaaa"); err != ErrDigestNotFound {
+		t.Errorf("Lookup after Remove = %v, want ErrDigestNotFound", err)
+	}
+}
+
+func TestDigestSetMultipleAlgorithms(t *testing.T) {
+	ds := NewDigestSet()
+
+	sha256d := digest.Digest("sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	sha512d := digest.Digest("sha512:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	ds.Add(sha256d)
+	ds.Add(sha512d)
+
+	got, err := ds.Lookup("sha512:aaaa")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if got != sha512d {
+		t.Errorf("Lookup(%q) = %q, want %q", "sha512:aaaa", got, sha512d)
+	}
+
+	all := ds.All()
+	if len(all) != 2 {
+		t.Fatalf("All() = %v, want 2 entries", all)
+	}
+}
+
+func TestParseAnyReferenceNamed(t *testing.T) {
+	ref, err := ParseAnyReference("ubuntu:latest", nil)
+	if err != nil {
+		t.Fatalf("ParseAnyReference: %v", err)
+	}
+	if ref.String() != "ubuntu:latest" {
+		t.Errorf("ParseAnyReference(%q).String() = %q", "ubuntu:latest", ref.String())
+	}
+}
+
+func TestParseAnyReferenceFullDigest(t *testing.T) {
+	s := "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+	ref, err := ParseAnyReference(s, nil)
+	if err != nil {
+		t.Fatalf("ParseAnyReference: %v", err)
+	}
+	canonical, ok := ref.(interface{ Digest() digest.Digest })
+	if !ok {
+		t.Fatalf("ParseAnyReference(%q) = %T, want a Canonical", s, ref)
+	}
+	if got, want := canonical.Digest().String(), s; got != want {
+		t.Errorf("Digest() = %q, want %q", got, want)
+	}
+}
+
+func TestParseAnyReferenceShortID(t *testing.T) {
+	d := digest.Digest("sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	ds := NewDigestSet()
+	ds.Add(d)
+
+	ref, err := ParseAnyReference("aaaaaaa", ds)
+	if err != nil {
+		t.Fatalf("ParseAnyReference: %v", err)
+	}
+	if ref.String() != d.String() {
+		t.Errorf("ParseAnyReference(%q).String() = %q, want %q", "aaaaaaa", ref.String(), d.String())
+	}
+
+	if _, err := ParseAnyReference("aaaaaaa", nil); err == nil {
+		t.Errorf("ParseAnyReference(%q, nil) succeeded, want error", "aaaaaaa")
+	}
+}
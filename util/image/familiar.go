@@ -0,0 +1,105 @@
+package image
+
+import (
+	"path"
+	"strings"
+)
+
+// Reference is the minimal contract required to format or match an image
+// reference in its familiar form. Named, NamedTagged and Canonical all
+// satisfy it already, since String() is part of Named.
+type Reference interface {
+	String() string
+}
+
+// familiar is implemented by references that know how to shorten
+// themselves, mirroring the normalizedNamed approach used by upstream
+// distribution/reference.
+type familiar interface {
+	Familiar() Named
+}
+
+// Familiar returns a shorthand version of the reference, with the default
+// registry hostname and "library/" prefix stripped when present. It is
+// meant for UI, logs and policy files, where "ubuntu:latest" reads a lot
+// better than "index.docker.io/library/ubuntu:latest". The same Normalizer
+// that produced r is used to decide what counts as "default".
+func (r *namedRef) Familiar() Named {
+	norm := r.usedNormalizer()
+	hostname, remoteName := norm.splitHostnamePreMirror(r.Name())
+	if hostname != norm.DefaultRegistry {
+		return r
+	}
+	fam, err := norm.WithName(strings.TrimPrefix(remoteName, norm.DefaultRepoPrefix))
+	if err != nil {
+		return r
+	}
+	return fam
+}
+
+// Familiar preserves the tag while shortening the underlying name.
+func (r *taggedRef) Familiar() Named {
+	fam := r.namedRef.Familiar()
+	tagged, err := WithTag(fam, r.Tag())
+	if err != nil {
+		return r
+	}
+	return tagged
+}
+
+// Familiar preserves the digest while shortening the underlying name.
+func (r *canonicalRef) Familiar() Named {
+	fam := r.namedRef.Familiar()
+	canonical, err := WithDigest(fam, r.Digest())
+	if err != nil {
+		return r
+	}
+	return canonical
+}
+
+// FamiliarName returns the familiar name string for the given named
+// reference, ie: "ubuntu" rather than "index.docker.io/library/ubuntu".
+func FamiliarName(ref Named) string {
+	if f, ok := ref.(familiar); ok {
+		return f.Familiar().Name()
+	}
+	return ref.Name()
+}
+
+// FamiliarString returns the familiar form of ref, including its tag or
+// digest when present.
+func FamiliarString(ref Reference) string {
+	if named, ok := ref.(Named); ok {
+		return FamiliarName(named) + refSuffix(ref)
+	}
+	return ref.String()
+}
+
+// FamiliarMatch reports whether ref's familiar form matches pattern, using
+// path.Match semantics. When ref is also a Named, FamiliarMatch falls back
+// to matching pattern against the un-familiarized name (ie: RemoteName,
+// which keeps the "library/" prefix) so that policies can be written
+// either way, eg "library/*" or "myorg/*:v1.*".
+func FamiliarMatch(pattern string, ref Reference) (bool, error) {
+	matched, err := path.Match(pattern, FamiliarString(ref))
+	if err != nil || matched {
+		return matched, err
+	}
+	if named, ok := ref.(Named); ok {
+		return path.Match(pattern, named.RemoteName()+refSuffix(ref))
+	}
+	return false, nil
+}
+
+// refSuffix returns the ":tag" or "@digest" portion of ref, or "" if ref
+// carries neither.
+func refSuffix(ref Reference) string {
+	switch x := ref.(type) {
+	case Canonical:
+		return "@" + x.Digest().String()
+	case NamedTagged:
+		return ":" + x.Tag()
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,107 @@
+package image
+
+import "testing"
+
+func TestFamiliarRoundTrip(t *testing.T) {
+	tests := []string{
+		"ubuntu",
+		"ubuntu:latest",
+		"library/ubuntu:18.04",
+		"myorg/app:v1.2",
+		"registry.internal/team/app:v1",
+		"index.docker.io/library/ubuntu:latest",
+	}
+
+	for _, s := range tests {
+		t.Run(s, func(t *testing.T) {
+			named, err := ParseNamed(s)
+			if err != nil {
+				t.Fatalf("ParseNamed(%q): %v", s, err)
+			}
+
+			again, err := ParseNamed(FamiliarString(named))
+			if err != nil {
+				t.Fatalf("ParseNamed(FamiliarString(%q)): %v", s, err)
+			}
+			if got, want := again.String(), named.String(); got != want {
+				t.Errorf("round trip = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestFamiliarStripsDefaultRegistry(t *testing.T) {
+	named, err := ParseNamed("index.docker.io/library/ubuntu:latest")
+	if err != nil {
+		t.Fatalf("ParseNamed: %v", err)
+	}
+
+	if got, want := FamiliarName(named), "ubuntu"; got != want {
+		t.Errorf("FamiliarName() = %q, want %q", got, want)
+	}
+	if got, want := FamiliarString(named), "ubuntu:latest"; got != want {
+		t.Errorf("FamiliarString() = %q, want %q", got, want)
+	}
+}
+
+func TestFamiliarLeavesNonDefaultRegistryAlone(t *testing.T) {
+	named, err := ParseNamed("registry.example.com/team/app:v1")
+	if err != nil {
+		t.Fatalf("ParseNamed: %v", err)
+	}
+
+	if got, want := FamiliarString(named), "registry.example.com/team/app:v1"; got != want {
+		t.Errorf("FamiliarString() = %q, want %q", got, want)
+	}
+}
+
+func TestFamiliarStripsDefaultRegistryWithMirrorConfigured(t *testing.T) {
+	n := &Normalizer{
+		DefaultRegistry:   DefaultRegistryHostname,
+		LegacyRegistries:  []string{WrongRegistryHostname},
+		DefaultRepoPrefix: DefaultRepoPrefix,
+		RegistryMirrors:   map[string]string{DefaultRegistryHostname: "registry.internal/proxy"},
+	}
+
+	named, err := n.ParseNamed("nginx:1.19")
+	if err != nil {
+		t.Fatalf("ParseNamed: %v", err)
+	}
+
+	// FullName/Hostname apply the mirror, so the unmirrored assertion below
+	// would fail if Familiar() compared the post-mirror hostname against
+	// n.DefaultRegistry instead of the pre-mirror one.
+	if got, want := named.FullName(), "registry.internal/proxy/library/nginx"; got != want {
+		t.Fatalf("FullName() = %q, want %q", got, want)
+	}
+
+	if got, want := FamiliarName(named), "nginx"; got != want {
+		t.Errorf("FamiliarName() = %q, want %q", got, want)
+	}
+	if got, want := FamiliarString(named), "nginx:1.19"; got != want {
+		t.Errorf("FamiliarString() = %q, want %q", got, want)
+	}
+}
+
+func TestFamiliarMatchFallsBackToRemoteName(t *testing.T) {
+	named, err := ParseNamed("ubuntu:latest")
+	if err != nil {
+		t.Fatalf("ParseNamed: %v", err)
+	}
+
+	matched, err := FamiliarMatch("library/*", named)
+	if err != nil {
+		t.Fatalf("FamiliarMatch: %v", err)
+	}
+	if !matched {
+		t.Errorf("FamiliarMatch(%q, %q) = false, want true", "library/*", named.String())
+	}
+
+	matched, err = FamiliarMatch("ubuntu:*", named)
+	if err != nil {
+		t.Fatalf("FamiliarMatch: %v", err)
+	}
+	if !matched {
+		t.Errorf("FamiliarMatch(%q, %q) = false, want true", "ubuntu:*", named.String())
+	}
+}
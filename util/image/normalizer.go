@@ -0,0 +1,159 @@
+package image
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/distribution/distribution/v3/reference"
+)
+
+// Normalizer holds the registry settings used to turn a possibly-partial
+// image name into this package's canonical Named form. It exists so Keel
+// can be pointed at a mirrored or air-gapped registry (eg: parsing
+// "nginx" as "registry.internal/proxy/library/nginx") instead of being
+// hardwired to Docker Hub.
+type Normalizer struct {
+	// DefaultRegistry is the hostname substituted for name-only references,
+	// such as "nginx" or "library/nginx".
+	DefaultRegistry string
+	// LegacyRegistries are hostnames rewritten to DefaultRegistry, the way
+	// "docker.io" is rewritten to "index.docker.io" today.
+	LegacyRegistries []string
+	// DefaultRepoPrefix is prepended to name-only repositories hosted on
+	// DefaultRegistry, such as "library/" for official Docker images.
+	DefaultRepoPrefix string
+	// RegistryMirrors maps a source hostname to the hostname it should be
+	// rewritten to, eg: {"docker.io": "registry.internal/proxy"}.
+	RegistryMirrors map[string]string
+}
+
+// DefaultNormalizer is the Normalizer used by the package-level Parse,
+// ParseNamed, ParseRepo and WithName helpers. Call SetDefaultNormalizer to
+// change it.
+var DefaultNormalizer = &Normalizer{
+	DefaultRegistry:   DefaultRegistryHostname,
+	LegacyRegistries:  []string{WrongRegistryHostname},
+	DefaultRepoPrefix: DefaultRepoPrefix,
+}
+
+// SetDefaultNormalizer replaces DefaultNormalizer. It is meant to be called
+// once during Keel's startup, before any reference parsing happens.
+func SetDefaultNormalizer(n *Normalizer) {
+	DefaultNormalizer = n
+}
+
+// isLegacy reports whether hostname is one of n.LegacyRegistries, ignoring
+// case.
+func (n *Normalizer) isLegacy(hostname string) bool {
+	for _, legacy := range n.LegacyRegistries {
+		if strings.EqualFold(hostname, legacy) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitHostnamePreMirror is SplitHostname without the final RegistryMirrors
+// rewrite: it decides the hostname and remoteName - including whether
+// n.DefaultRepoPrefix applies - purely from n.DefaultRegistry and
+// n.LegacyRegistries, before any mirror is taken into account. normalize
+// uses this (rather than SplitHostname) so that a name's canonical Name()
+// depends only on whether it's hosted on the configured default/legacy
+// registry, not on where a mirror happens to redirect it today.
+func (n *Normalizer) splitHostnamePreMirror(name string) (hostname, remoteName string) {
+	i := strings.IndexRune(name, '/')
+	if i == -1 || (!strings.ContainsAny(name[:i], ".:") && !strings.EqualFold(name[:i], "localhost")) {
+		hostname, remoteName = n.DefaultRegistry, name
+	} else {
+		hostname, remoteName = name[:i], name[i+1:]
+	}
+	if n.isLegacy(hostname) || strings.EqualFold(hostname, n.DefaultRegistry) {
+		hostname = n.DefaultRegistry
+	}
+	if hostname == n.DefaultRegistry && !strings.ContainsRune(remoteName, '/') {
+		remoteName = n.DefaultRepoPrefix + remoteName
+	}
+
+	return
+}
+
+// SplitHostname splits a repository name into hostname and remotename,
+// applying n's legacy rewrites and mirrors and falling back to
+// n.DefaultRegistry when no hostname is found. name is assumed to already
+// be validated.
+//
+// The hostname portion is matched case-insensitively against
+// n.DefaultRegistry and n.LegacyRegistries (mirroring upstream Docker,
+// which allows uppercase letters in the registry domain) but is otherwise
+// returned exactly as provided; only remoteName is required to be
+// lowercase.
+func (n *Normalizer) SplitHostname(name string) (hostname, remoteName string) {
+	hostname, remoteName = n.splitHostnamePreMirror(name)
+	if mirror, ok := n.RegistryMirrors[hostname]; ok {
+		hostname = mirror
+	}
+	return
+}
+
+// normalize returns a repository name in its normalized form, meaning it
+// will not contain n.DefaultRegistry nor n.DefaultRepoPrefix for official
+// images. Only the path components following the hostname are required to
+// be lowercase; the hostname itself may contain uppercase letters.
+//
+// normalize deliberately ignores RegistryMirrors: the canonical Name() it
+// produces must stay stable so that FullName/Hostname (which do apply
+// mirrors, via SplitHostname) can re-derive the current mirror target from
+// it on every call, rather than baking today's mirror into the name.
+func (n *Normalizer) normalize(name string) (string, error) {
+	host, remoteName := n.splitHostnamePreMirror(name)
+	if strings.ToLower(remoteName) != remoteName {
+		return "", errors.New("invalid reference format: repository name must be lowercase")
+	}
+	if host == n.DefaultRegistry {
+		if strings.HasPrefix(remoteName, n.DefaultRepoPrefix) {
+			return strings.TrimPrefix(remoteName, n.DefaultRepoPrefix), nil
+		}
+		return remoteName, nil
+	}
+	return host + "/" + remoteName, nil
+}
+
+// ParseNamed parses s the same way the package-level ParseNamed does, but
+// using n's registry settings instead of the built-in Docker Hub defaults.
+func (n *Normalizer) ParseNamed(s string) (Named, error) {
+	named, err := reference.ParseNormalizedNamed(s)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing reference: %q is not a valid repository/tag, error: %s", s, err)
+	}
+
+	r, err := n.WithName(named.Name())
+	if err != nil {
+		return nil, err
+	}
+	if canonical, isCanonical := named.(reference.Canonical); isCanonical {
+		return WithDigest(r, canonical.Digest())
+	}
+
+	if tagged, isTagged := named.(reference.NamedTagged); isTagged {
+		return WithTag(r, tagged.Tag())
+	}
+	return r, nil
+}
+
+// WithName returns a named object representing the given string, using n's
+// registry settings instead of the built-in Docker Hub defaults.
+func (n *Normalizer) WithName(name string) (Named, error) {
+	name, err := n.normalize(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	r, err := reference.WithName(name)
+	if err != nil {
+		return nil, err
+	}
+	return &namedRef{Named: r, normalizer: n}, nil
+}
@@ -0,0 +1,98 @@
+package image
+
+import "testing"
+
+func TestNormalizerMirrorRewriting(t *testing.T) {
+	n := &Normalizer{
+		DefaultRegistry:   DefaultRegistryHostname,
+		LegacyRegistries:  []string{WrongRegistryHostname},
+		DefaultRepoPrefix: DefaultRepoPrefix,
+		RegistryMirrors:   map[string]string{DefaultRegistryHostname: "registry.internal/proxy"},
+	}
+
+	named, err := n.ParseNamed("nginx")
+	if err != nil {
+		t.Fatalf("ParseNamed: %v", err)
+	}
+
+	if got, want := named.FullName(), "registry.internal/proxy/library/nginx"; got != want {
+		t.Errorf("FullName() = %q, want %q", got, want)
+	}
+	if got, want := named.Hostname(), "registry.internal/proxy"; got != want {
+		t.Errorf("Hostname() = %q, want %q", got, want)
+	}
+	if got, want := named.Name(), "nginx"; got != want {
+		t.Errorf("Name() = %q, want %q", got, want)
+	}
+}
+
+func TestNormalizerRoundTripThroughString(t *testing.T) {
+	n := &Normalizer{
+		DefaultRegistry:   DefaultRegistryHostname,
+		LegacyRegistries:  []string{WrongRegistryHostname},
+		DefaultRepoPrefix: DefaultRepoPrefix,
+		RegistryMirrors:   map[string]string{DefaultRegistryHostname: "registry.internal/proxy"},
+	}
+
+	named, err := n.ParseNamed("nginx:1.19")
+	if err != nil {
+		t.Fatalf("ParseNamed: %v", err)
+	}
+
+	again, err := n.ParseNamed(named.String())
+	if err != nil {
+		t.Fatalf("ParseNamed(%q): %v", named.String(), err)
+	}
+	if again.String() != named.String() {
+		t.Errorf("round-trip mismatch: %q != %q", again.String(), named.String())
+	}
+}
+
+func TestNormalizerLegacyHostnameCollapse(t *testing.T) {
+	n := &Normalizer{
+		DefaultRegistry:   DefaultRegistryHostname,
+		LegacyRegistries:  []string{WrongRegistryHostname},
+		DefaultRepoPrefix: DefaultRepoPrefix,
+	}
+
+	named, err := n.ParseNamed("docker.io/library/nginx")
+	if err != nil {
+		t.Fatalf("ParseNamed: %v", err)
+	}
+
+	if got, want := named.Name(), "nginx"; got != want {
+		t.Errorf("Name() = %q, want %q", got, want)
+	}
+	if got, want := named.Hostname(), DefaultRegistryHostname; got != want {
+		t.Errorf("Hostname() = %q, want %q", got, want)
+	}
+}
+
+func TestNormalizerIsNameOnlyAndWithDefaultTagWithMirrors(t *testing.T) {
+	n := &Normalizer{
+		DefaultRegistry:   DefaultRegistryHostname,
+		LegacyRegistries:  []string{WrongRegistryHostname},
+		DefaultRepoPrefix: DefaultRepoPrefix,
+		RegistryMirrors:   map[string]string{DefaultRegistryHostname: "registry.internal/proxy"},
+	}
+
+	named, err := n.ParseNamed("nginx")
+	if err != nil {
+		t.Fatalf("ParseNamed: %v", err)
+	}
+
+	if !IsNameOnly(named) {
+		t.Fatalf("IsNameOnly(%q) = false, want true", named.Name())
+	}
+
+	tagged := WithDefaultTag(named)
+	if IsNameOnly(tagged) {
+		t.Fatalf("IsNameOnly(WithDefaultTag(%q)) = true, want false", named.Name())
+	}
+	if got, want := tagged.String(), "nginx:"+DefaultTag; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got, want := tagged.FullName(), "registry.internal/proxy/library/nginx"; got != want {
+		t.Errorf("FullName() = %q, want %q", got, want)
+	}
+}
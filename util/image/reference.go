@@ -1,9 +1,7 @@
 package image
 
 import (
-	"errors"
 	"fmt"
-	"strings"
 
 	// "github.com/docker/distribution/digest"
 	"github.com/distribution/distribution/v3/reference"
@@ -25,17 +23,6 @@ const (
 	DefaultRepoPrefix = "library/"
 )
 
-// Repository is an object created from Named interface
-type Repository struct {
-	Name       string // Name returns the image's name. (ie: debian[:8.2])
-	Repository string // Repository returns the image's repository. (ie: registry/name)
-	Registry   string // Registry returns the image's registry. (ie: host[:port])
-	Scheme     string // Registry scheme. (ie: http)
-	ShortName  string // ShortName returns the image's name (ie: debian)
-	Remote     string // Remote returns the image's remote identifier. (ie: registry/name[:tag])
-	Tag        string // Tag returns the image's tag (or digest).
-}
-
 // Named is an object with a full name
 type Named interface {
 	// Name returns normalized repository name, like "ubuntu".
@@ -67,42 +54,20 @@ type Canonical interface {
 // the Named interface. The reference must have a name, otherwise an error is
 // returned.
 // If an error was encountered it is returned, along with a nil Reference.
+//
+// ParseNamed uses DefaultNormalizer; call DefaultNormalizer.ParseNamed (or
+// SetDefaultNormalizer) to use different registry settings.
 func ParseNamed(s string) (Named, error) {
-
-	named, err := reference.ParseNormalizedNamed(s)
-	if err != nil {
-		return nil, fmt.Errorf("Error parsing reference: %q is not a valid repository/tag, error: %s", s, err)
-	}
-
-	r, err := WithName(named.Name())
-	if err != nil {
-		return nil, err
-	}
-	if canonical, isCanonical := named.(reference.Canonical); isCanonical {
-		return WithDigest(r, canonical.Digest())
-	}
-
-	if tagged, isTagged := named.(reference.NamedTagged); isTagged {
-		return WithTag(r, tagged.Tag())
-	}
-	return r, nil
+	return DefaultNormalizer.ParseNamed(s)
 }
 
 // WithName returns a named object representing the given string. If the input
 // is invalid ErrReferenceInvalidFormat will be returned.
+//
+// WithName uses DefaultNormalizer; call DefaultNormalizer.WithName (or
+// SetDefaultNormalizer) to use different registry settings.
 func WithName(name string) (Named, error) {
-	name, err := normalize(name)
-	if err != nil {
-		return nil, err
-	}
-	if err := validateName(name); err != nil {
-		return nil, err
-	}
-	r, err := reference.WithName(name)
-	if err != nil {
-		return nil, err
-	}
-	return &namedRef{r}, nil
+	return DefaultNormalizer.WithName(name)
 }
 
 // WithTag combines the name from "name" and the tag from "tag" to form a
@@ -112,7 +77,7 @@ func WithTag(name Named, tag string) (NamedTagged, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &taggedRef{namedRef{r}}, nil
+	return &taggedRef{namedRef{Named: r, normalizer: normalizerOf(name)}}, nil
 }
 
 // WithDigest combines the name from "name" and the digest from "digest" to form
@@ -122,11 +87,16 @@ func WithDigest(name Named, digest digest.Digest) (Canonical, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &canonicalRef{namedRef{r}}, nil
+	return &canonicalRef{namedRef{Named: r, normalizer: normalizerOf(name)}}, nil
 }
 
 type namedRef struct {
 	reference.Named
+	// normalizer is the Normalizer that produced this reference, so that
+	// FullName/Hostname/RemoteName (and Familiar) reconstruct the domain
+	// using the same registry settings the name was stripped with. nil
+	// means DefaultNormalizer.
+	normalizer *Normalizer
 }
 type taggedRef struct {
 	namedRef
@@ -135,16 +105,40 @@ type canonicalRef struct {
 	namedRef
 }
 
+// normalizerOf returns the Normalizer that produced named, or nil (meaning
+// DefaultNormalizer) if named isn't one of this package's own types.
+func normalizerOf(named Named) *Normalizer {
+	switch n := named.(type) {
+	case *namedRef:
+		return n.normalizer
+	case *taggedRef:
+		return n.namedRef.normalizer
+	case *canonicalRef:
+		return n.namedRef.normalizer
+	default:
+		return nil
+	}
+}
+
+// usedNormalizer returns the Normalizer that produced r, falling back to
+// DefaultNormalizer.
+func (r *namedRef) usedNormalizer() *Normalizer {
+	if r.normalizer != nil {
+		return r.normalizer
+	}
+	return DefaultNormalizer
+}
+
 func (r *namedRef) FullName() string {
-	hostname, remoteName := splitHostname(r.Name())
+	hostname, remoteName := r.usedNormalizer().SplitHostname(r.Name())
 	return hostname + "/" + remoteName
 }
 func (r *namedRef) Hostname() string {
-	hostname, _ := splitHostname(r.Name())
+	hostname, _ := r.usedNormalizer().SplitHostname(r.Name())
 	return hostname
 }
 func (r *namedRef) RemoteName() string {
-	_, remoteName := splitHostname(r.Name())
+	_, remoteName := r.usedNormalizer().SplitHostname(r.Name())
 	return remoteName
 }
 func (r *taggedRef) Tag() string {
@@ -176,37 +170,11 @@ func IsNameOnly(ref Named) bool {
 // splitHostname splits a repository name to hostname and remotename string.
 // If no valid hostname is found, the default hostname is used. Repository name
 // needs to be already validated before.
+//
+// splitHostname uses DefaultNormalizer; call DefaultNormalizer.SplitHostname
+// (or SetDefaultNormalizer) to use different registry settings.
 func splitHostname(name string) (hostname, remoteName string) {
-	i := strings.IndexRune(name, '/')
-	if i == -1 || (!strings.ContainsAny(name[:i], ".:") && name[:i] != "localhost") {
-		hostname, remoteName = DefaultRegistryHostname, name
-	} else {
-		hostname, remoteName = name[:i], name[i+1:]
-	}
-	if hostname == WrongRegistryHostname {
-		hostname = DefaultRegistryHostname
-	}
-	if hostname == DefaultRegistryHostname && !strings.ContainsRune(remoteName, '/') {
-		remoteName = DefaultRepoPrefix + remoteName
-	}
-
-	return
-}
-
-// normalize returns a repository name in its normalized form, meaning it
-// will not contain default hostname nor library/ prefix for official images.
-func normalize(name string) (string, error) {
-	host, remoteName := splitHostname(name)
-	if strings.ToLower(remoteName) != remoteName {
-		return "", errors.New("invalid reference format: repository name must be lowercase")
-	}
-	if host == DefaultRegistryHostname {
-		if strings.HasPrefix(remoteName, DefaultRepoPrefix) {
-			return strings.TrimPrefix(remoteName, DefaultRepoPrefix), nil
-		}
-		return remoteName, nil
-	}
-	return name, nil
+	return DefaultNormalizer.SplitHostname(name)
 }
 
 func validateName(name string) error {